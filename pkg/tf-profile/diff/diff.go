@@ -0,0 +1,209 @@
+package tfprofile
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	. "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/aggregate"
+	. "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/core"
+	. "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/parser"
+	. "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/readers"
+	"github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/render"
+	. "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/utils"
+)
+
+// Execute the `tf-profile diff <old.log> <new.log>` command.
+func Diff(oldFile string, newFile string, tee bool, thresholdSeconds float64, thresholdPercent float64, format string) error {
+	oldLog, err := parseLog(oldFile, tee)
+	if err != nil {
+		return err
+	}
+
+	newLog, err := parseLog(newFile, tee)
+	if err != nil {
+		return err
+	}
+
+	return PrintDiff(oldLog, newLog, thresholdSeconds, thresholdPercent, format)
+}
+
+func parseLog(file string, tee bool) (ParsedLog, error) {
+	scanner, err := FileReader{File: file}.Read()
+	if err != nil {
+		return ParsedLog{}, err
+	}
+
+	log, err := Parse(scanner, tee)
+	if err != nil {
+		return ParsedLog{}, err
+	}
+
+	return Aggregate(log)
+}
+
+// Print a side-by-side comparison of two ParsedLogs: per-resource deltas in
+// TotalTime, NumCalls, Operation and AfterStatus, plus new/removed resources
+// and a summary mirroring getTimeStats/getAfterStatusStats.
+// A resource's delta is suppressed unless its apply time changed by at least
+// thresholdSeconds or thresholdPercent (new/removed resources and operation
+// changes are always reported). format selects the Renderer: text|json|ndjson
+// (csv returns an error for diff output).
+func PrintDiff(oldLog ParsedLog, newLog ParsedLog, thresholdSeconds float64, thresholdPercent float64, format string) error {
+	renderer, err := render.New(format)
+	if err != nil {
+		return err
+	}
+
+	report := render.DiffReport{
+		Resources: diffResources(oldLog, newLog, thresholdSeconds, thresholdPercent),
+		Summary:   diffSummary(oldLog, newLog),
+	}
+
+	return renderer.RenderDiff(report)
+}
+
+func diffResources(oldLog ParsedLog, newLog ParsedLog, thresholdSeconds float64, thresholdPercent float64) []render.ResourceDelta {
+	names := make(map[string]bool)
+	for name := range oldLog.Resources {
+		names[name] = true
+	}
+	for name := range newLog.Resources {
+		names[name] = true
+	}
+
+	result := []render.ResourceDelta{}
+	for name := range names {
+		old, hadOld := oldLog.Resources[name]
+		new_, hadNew := newLog.Resources[name]
+
+		switch {
+		case !hadOld:
+			result = append(result, render.ResourceDelta{
+				Resource:           name,
+				Status:             "new",
+				NewTotalTimeMillis: int64(new_.TotalTime),
+				DeltaTimeMillis:    int64(new_.TotalTime),
+				DeltaPercent:       100,
+				NewNumCalls:        new_.NumCalls,
+				NewOperation:       new_.Operation.String(),
+				NewAfterStatus:     new_.AfterStatus.String(),
+			})
+		case !hadNew:
+			result = append(result, render.ResourceDelta{
+				Resource:           name,
+				Status:             "removed",
+				OldTotalTimeMillis: int64(old.TotalTime),
+				DeltaTimeMillis:    -int64(old.TotalTime),
+				DeltaPercent:       -100,
+				OldNumCalls:        old.NumCalls,
+				OldOperation:       old.Operation.String(),
+				OldAfterStatus:     old.AfterStatus.String(),
+			})
+		default:
+			deltaMillis := int64(new_.TotalTime) - int64(old.TotalTime)
+			deltaPercent := percentChange(float64(old.TotalTime), float64(new_.TotalTime))
+			operationChanged := old.Operation.String() != new_.Operation.String()
+			statusChanged := old.AfterStatus.String() != new_.AfterStatus.String()
+
+			if !shouldReportChange(deltaMillis, deltaPercent, operationChanged, statusChanged, thresholdSeconds, thresholdPercent) {
+				continue
+			}
+
+			result = append(result, render.ResourceDelta{
+				Resource:           name,
+				Status:             "changed",
+				OldTotalTimeMillis: int64(old.TotalTime),
+				NewTotalTimeMillis: int64(new_.TotalTime),
+				DeltaTimeMillis:    deltaMillis,
+				DeltaPercent:       deltaPercent,
+				OldNumCalls:        old.NumCalls,
+				NewNumCalls:        new_.NumCalls,
+				OldOperation:       old.Operation.String(),
+				NewOperation:       new_.Operation.String(),
+				OldAfterStatus:     old.AfterStatus.String(),
+				NewAfterStatus:     new_.AfterStatus.String(),
+			})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Resource < result[j].Resource
+	})
+
+	return result
+}
+
+func diffSummary(oldLog ParsedLog, newLog ParsedLog) []render.StatRow {
+	oldTotal, oldCalls := totalTimeAndCalls(oldLog)
+	newTotal, newCalls := totalTimeAndCalls(newLog)
+
+	deltaTotal := newTotal - oldTotal
+	deltaPercent := percentChange(float64(oldTotal), float64(newTotal))
+
+	return []render.StatRow{
+		{Name: "Cumulative duration (old)", Value: FormatDuration(int(oldTotal / 1000))},
+		{Name: "Cumulative duration (new)", Value: FormatDuration(int(newTotal / 1000))},
+		{Name: "Cumulative duration delta", Value: fmt.Sprintf("%v%v (%+.1f%%)", signOf(deltaTotal), FormatDuration(int(math.Abs(float64(deltaTotal))/1000)), deltaPercent)},
+		{Name: "Resources (old)", Value: fmt.Sprint(oldCalls)},
+		{Name: "Resources (new)", Value: fmt.Sprint(newCalls)},
+		{Name: "Resources added", Value: fmt.Sprint(countMissing(oldLog, newLog))},
+		{Name: "Resources removed", Value: fmt.Sprint(countMissing(newLog, oldLog))},
+	}
+}
+
+func totalTimeAndCalls(log ParsedLog) (int64, int) {
+	var totalTime int64
+	calls := 0
+	for _, metric := range log.Resources {
+		totalTime += int64(metric.TotalTime)
+		calls += metric.NumCalls
+	}
+	return totalTime, calls
+}
+
+// countMissing returns how many resources in b are absent from a.
+func countMissing(a ParsedLog, b ParsedLog) int {
+	count := 0
+	for name := range b.Resources {
+		if _, ok := a.Resources[name]; !ok {
+			count++
+		}
+	}
+	return count
+}
+
+// shouldReportChange decides whether a resource's delta is worth reporting.
+// An exact no-op (no time delta and no operation/status change) is always
+// suppressed regardless of threshold - otherwise the out-of-the-box (0, 0)
+// threshold would never suppress anything, since "< 0" is never true. A
+// changed operation or final state is always reported, since --threshold
+// only governs apply-time noise. Otherwise the resource is suppressed only
+// if its time changed by less than *both* thresholdSeconds and
+// thresholdPercent.
+func shouldReportChange(deltaMillis int64, deltaPercent float64, operationChanged bool, statusChanged bool, thresholdSeconds float64, thresholdPercent float64) bool {
+	if operationChanged || statusChanged {
+		return true
+	}
+	if deltaMillis == 0 {
+		return false
+	}
+	return math.Abs(float64(deltaMillis))/1000 >= thresholdSeconds || math.Abs(deltaPercent) >= thresholdPercent
+}
+
+func percentChange(old float64, new_ float64) float64 {
+	if old == 0 {
+		if new_ == 0 {
+			return 0
+		}
+		return 100
+	}
+	return 100 * (new_ - old) / old
+}
+
+func signOf(v int64) string {
+	if v >= 0 {
+		return "+"
+	}
+	return "-"
+}
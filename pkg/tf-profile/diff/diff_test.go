@@ -0,0 +1,80 @@
+package tfprofile
+
+import (
+	"testing"
+
+	. "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/core"
+)
+
+func TestDiffResourcesIdenticalLogs(t *testing.T) {
+	log := ParsedLog{Resources: map[string]ResourceMetric{
+		"aws_instance.a": {NumCalls: 1, TotalTime: 5000},
+		"aws_instance.b": {NumCalls: 2, TotalTime: 12000},
+	}}
+
+	deltas := diffResources(log, log, 0, 0)
+
+	if len(deltas) != 0 {
+		t.Fatalf("expected no deltas for identical logs, got %+v", deltas)
+	}
+}
+
+func TestDiffResourcesNewAndRemoved(t *testing.T) {
+	old := ParsedLog{Resources: map[string]ResourceMetric{
+		"aws_instance.removed": {NumCalls: 1, TotalTime: 1000},
+		"aws_instance.common":  {NumCalls: 1, TotalTime: 1000},
+	}}
+	new_ := ParsedLog{Resources: map[string]ResourceMetric{
+		"aws_instance.new":    {NumCalls: 1, TotalTime: 2000},
+		"aws_instance.common": {NumCalls: 1, TotalTime: 1000},
+	}}
+
+	deltas := diffResources(old, new_, 0, 0)
+
+	status := make(map[string]string, len(deltas))
+	for _, d := range deltas {
+		status[d.Resource] = d.Status
+	}
+
+	if status["aws_instance.new"] != "new" {
+		t.Errorf("expected aws_instance.new to be reported as new, got %+v", status)
+	}
+	if status["aws_instance.removed"] != "removed" {
+		t.Errorf("expected aws_instance.removed to be reported as removed, got %+v", status)
+	}
+	if _, reported := status["aws_instance.common"]; reported {
+		t.Errorf("expected unchanged aws_instance.common to be suppressed, got %+v", status)
+	}
+}
+
+func TestShouldReportChange(t *testing.T) {
+	tests := []struct {
+		name             string
+		deltaMillis      int64
+		deltaPercent     float64
+		operationChanged bool
+		statusChanged    bool
+		thresholdSeconds float64
+		thresholdPercent float64
+		want             bool
+	}{
+		{"exact no-op at default (0,0) threshold is suppressed", 0, 0, false, false, 0, 0, false},
+		{"exact no-op is suppressed even with a nonzero threshold", 0, 0, false, false, 5, 10, false},
+		{"any nonzero change is reported at the default (0,0) threshold", 500, 2, false, false, 0, 0, true},
+		{"change below both thresholds is suppressed", 500, 2, false, false, 5, 10, false},
+		{"change exceeding the seconds threshold is reported", 6000, 2, false, false, 5, 10, true},
+		{"change exceeding the percent threshold is reported", 500, 20, false, false, 5, 10, true},
+		{"operation change is always reported despite the threshold", 0, 0, true, false, 100, 100, true},
+		{"status change is always reported despite the threshold", 0, 0, false, true, 100, 100, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := shouldReportChange(tt.deltaMillis, tt.deltaPercent, tt.operationChanged, tt.statusChanged, tt.thresholdSeconds, tt.thresholdPercent)
+			if got != tt.want {
+				t.Errorf("shouldReportChange(%v, %v, %v, %v, %v, %v) = %v, want %v",
+					tt.deltaMillis, tt.deltaPercent, tt.operationChanged, tt.statusChanged, tt.thresholdSeconds, tt.thresholdPercent, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package tfprofile
+
+import "strings"
+
+// Segments splits a resource address ("module.a.module.b.type.name") into
+// its module path components (["a", "b"]); a root-module resource
+// ("type.name") returns nil. Shared by pkg/tf-profile/modules (the `modules`
+// tree) and pkg/tf-profile/prometheus (the `module` label) so both agree on
+// what a resource's module path looks like.
+func Segments(resource string) []string {
+	parts := strings.Split(resource, ".")
+	if len(parts) <= 2 {
+		return nil
+	}
+
+	moduleParts := parts[:len(parts)-2]
+	segments := make([]string, 0, len(moduleParts)/2)
+	for i := 0; i+1 < len(moduleParts); i += 2 {
+		segments = append(segments, moduleParts[i+1])
+	}
+	return segments
+}
+
+// Of returns the dotted module path of a resource address ("a.b" for
+// "module.a.module.b.type.name"), or "" for a root-module resource.
+func Of(resource string) string {
+	return strings.Join(Segments(resource), ".")
+}
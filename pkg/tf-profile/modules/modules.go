@@ -0,0 +1,168 @@
+package tfprofile
+
+import (
+	"bufio"
+	"sort"
+
+	. "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/aggregate"
+	. "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/core"
+	tfpmodulepath "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/modulepath"
+	. "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/parser"
+	. "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/readers"
+	"github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/render"
+)
+
+// Execute the `tf-profile modules` command.
+func Modules(args []string, tee bool, maxDepth int, format string) error {
+	var file *bufio.Scanner
+	var err error
+
+	if len(args) == 1 {
+		file, err = FileReader{File: args[0]}.Read()
+	} else {
+		file, err = StdinReader{}.Read()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	tflog, err := Parse(file, tee)
+	if err != nil {
+		return err
+	}
+
+	tflog, err = Aggregate(tflog)
+	if err != nil {
+		return err
+	}
+
+	return PrintModules(tflog, maxDepth, format)
+}
+
+// Print a ParsedLog as a tree of modules, each node showing resource count,
+// cumulative time, max time and percent of total apply duration.
+// maxDepth collapses every module deeper than it into its ancestor at that
+// depth; maxDepth <= 0 means unlimited.
+// format selects the Renderer used to print the result: text|json|csv|ndjson.
+func PrintModules(log ParsedLog, maxDepth int, format string) error {
+	renderer, err := render.New(format)
+	if err != nil {
+		return err
+	}
+
+	root := buildModuleTree(log, maxDepth)
+	return renderer.RenderModuleTree(root)
+}
+
+// moduleNode accumulates stats for one module path while the tree is built.
+// It's converted to render.ModuleNode (sorted, with percentages filled in)
+// once every resource has been assigned to a node.
+type moduleNode struct {
+	path          string
+	resourceCount int
+	cumulative    int64
+	max           int64
+	children      map[string]*moduleNode
+	order         []string
+}
+
+func newModuleNode(path string) *moduleNode {
+	return &moduleNode{path: path, children: make(map[string]*moduleNode)}
+}
+
+func (n *moduleNode) child(name string) *moduleNode {
+	if c, ok := n.children[name]; ok {
+		return c
+	}
+	c := newModuleNode(name)
+	n.children[name] = c
+	n.order = append(n.order, name)
+	return c
+}
+
+func (n *moduleNode) addResource(totalTimeMillis int64) {
+	n.resourceCount++
+	n.cumulative += totalTimeMillis
+	if totalTimeMillis > n.max {
+		n.max = totalTimeMillis
+	}
+}
+
+// buildModuleTree groups log.Resources by module path ("module.a.module.b")
+// into a tree rooted at the root module, collapsing anything deeper than
+// maxDepth (when maxDepth > 0) into the node at that depth.
+func buildModuleTree(log ParsedLog, maxDepth int) render.ModuleNode {
+	root := newModuleNode("")
+
+	var totalTime int64
+	for name, metric := range log.Resources {
+		totalTime += int64(metric.TotalTime)
+
+		segments := tfpmodulepath.Segments(name)
+		if maxDepth > 0 && len(segments) > maxDepth {
+			segments = segments[:maxDepth]
+		}
+
+		node := root
+		prefix := ""
+		for _, segment := range segments {
+			if prefix == "" {
+				prefix = segment
+			} else {
+				prefix = prefix + "." + segment
+			}
+			node = node.child(prefix)
+		}
+		node.addResource(int64(metric.TotalTime))
+	}
+
+	// Propagate counts up from leaves so every ancestor's totals include its
+	// descendants, not just resources declared directly in it.
+	rollUp(root)
+
+	return toRenderNode(root, totalTime)
+}
+
+// rollUp adds every node's children's totals into the node itself.
+func rollUp(n *moduleNode) {
+	for _, name := range n.order {
+		child := n.children[name]
+		rollUp(child)
+		n.resourceCount += child.resourceCount
+		n.cumulative += child.cumulative
+		if child.max > n.max {
+			n.max = child.max
+		}
+	}
+}
+
+// toRenderNode converts a moduleNode into a render.ModuleNode, computing
+// percentages against totalTimeMillis and sorting children by cumulative
+// time descending (ties broken by path for determinism).
+func toRenderNode(n *moduleNode, totalTimeMillis int64) render.ModuleNode {
+	children := make([]render.ModuleNode, 0, len(n.order))
+	for _, name := range n.order {
+		children = append(children, toRenderNode(n.children[name], totalTimeMillis))
+	}
+	sort.SliceStable(children, func(i, j int) bool {
+		if children[i].CumulativeTimeMillis != children[j].CumulativeTimeMillis {
+			return children[i].CumulativeTimeMillis > children[j].CumulativeTimeMillis
+		}
+		return children[i].Module < children[j].Module
+	})
+
+	var percent float64
+	if totalTimeMillis > 0 {
+		percent = 100 * float64(n.cumulative) / float64(totalTimeMillis)
+	}
+
+	return render.ModuleNode{
+		Module:               n.path,
+		ResourceCount:        n.resourceCount,
+		CumulativeTimeMillis: n.cumulative,
+		MaxTimeMillis:        n.max,
+		PercentOfTotal:       percent,
+		Children:             children,
+	}
+}
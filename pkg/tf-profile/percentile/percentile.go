@@ -0,0 +1,24 @@
+package tfprofile
+
+// Of returns the p-th percentile (0 <= p <= 1) of a pre-sorted slice,
+// linearly interpolating between the two nearest ranks. Shared by
+// pkg/tf-profile/stats (percentiles in `stats`) and pkg/tf-profile/prometheus
+// (quantiles in the exposition summary) so the two don't drift apart.
+func Of(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+
+	pos := p * float64(len(sorted)-1)
+	lower := int(pos)
+	upper := lower + 1
+	if upper >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+
+	frac := pos - float64(lower)
+	return sorted[lower] + frac*(sorted[upper]-sorted[lower])
+}
@@ -0,0 +1,130 @@
+package tfprofile
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	. "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/aggregate"
+	. "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/core"
+	tfpmodulepath "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/modulepath"
+	. "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/parser"
+	tfppercentile "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/percentile"
+	. "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/readers"
+)
+
+// Execute the `tf-profile prometheus` command. When pushgateway is empty the
+// exposition payload is printed to stdout; otherwise it is POSTed to the
+// given Pushgateway URL under job (defaulting to "terraform") and groupings.
+func Prometheus(args []string, tee bool, pushgateway string, job string, groupings map[string]string) error {
+	var file *bufio.Scanner
+	var err error
+
+	if len(args) == 1 {
+		file, err = FileReader{File: args[0]}.Read()
+	} else {
+		file, err = StdinReader{}.Read()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	tflog, err := Parse(file, tee)
+	if err != nil {
+		return err
+	}
+
+	tflog, err = Aggregate(tflog)
+	if err != nil {
+		return err
+	}
+
+	payload := FormatExposition(tflog)
+
+	if pushgateway != "" {
+		return push(pushgateway, job, groupings, payload)
+	}
+
+	fmt.Print(payload)
+	return nil
+}
+
+// FormatExposition converts a ParsedLog into Prometheus text exposition
+// format. Label sets and sample order are sorted by resource name so the
+// output is deterministic and can be diffed across runs.
+func FormatExposition(log ParsedLog) string {
+	var buf bytes.Buffer
+
+	names := make([]string, 0, len(log.Resources))
+	for name := range log.Resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buf.WriteString("# HELP terraform_resource_apply_duration_seconds Time spent applying a resource, in seconds.\n")
+	buf.WriteString("# TYPE terraform_resource_apply_duration_seconds gauge\n")
+	for _, name := range names {
+		metric := log.Resources[name]
+		fmt.Fprintf(&buf, "terraform_resource_apply_duration_seconds{resource=%q,module=%q,operation=%q,final_state=%q} %v\n",
+			name, tfpmodulepath.Of(name), metric.Operation.String(), metric.AfterStatus.String(), float64(metric.TotalTime)/1000)
+	}
+
+	buf.WriteString("# HELP terraform_resource_calls_total Number of calls Terraform made while applying a resource.\n")
+	buf.WriteString("# TYPE terraform_resource_calls_total gauge\n")
+	for _, name := range names {
+		metric := log.Resources[name]
+		fmt.Fprintf(&buf, "terraform_resource_calls_total{resource=%q,module=%q} %v\n", name, tfpmodulepath.Of(name), metric.NumCalls)
+	}
+
+	durations := make([]float64, 0, len(names))
+	for _, name := range names {
+		durations = append(durations, float64(log.Resources[name].TotalTime)/1000)
+	}
+	sort.Float64s(durations)
+
+	buf.WriteString("# HELP terraform_apply_duration_seconds Quantiles of per-resource apply duration across the run.\n")
+	buf.WriteString("# TYPE terraform_apply_duration_seconds summary\n")
+	for _, q := range []float64{0.5, 0.9, 0.99} {
+		fmt.Fprintf(&buf, "terraform_apply_duration_seconds{quantile=\"%v\"} %v\n", q, tfppercentile.Of(durations, q))
+	}
+
+	return buf.String()
+}
+
+// push POSTs the exposition payload to a Prometheus Pushgateway as a single
+// batch under the given job (and optional extra groupings).
+func push(pushgateway string, job string, groupings map[string]string, payload string) error {
+	if job == "" {
+		job = "terraform"
+	}
+
+	u, err := url.Parse(strings.TrimRight(pushgateway, "/") + "/metrics/job/" + url.PathEscape(job))
+	if err != nil {
+		return fmt.Errorf("invalid --pushgateway URL: %w", err)
+	}
+
+	keys := make([]string, 0, len(groupings))
+	for k := range groupings {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		u.Path += "/" + url.PathEscape(k) + "/" + url.PathEscape(groupings[k])
+	}
+
+	resp, err := http.Post(u.String(), "text/plain", strings.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to push to pushgateway: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("pushgateway returned status %v", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package render
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// CSVRenderer emits CSV with a stable column order matching the text table
+// headers, so the schema doesn't shuffle between runs for spreadsheet/BI tools.
+type CSVRenderer struct{}
+
+var resourceColumns = []string{"resource", "n", "tot_time_ms", "modify_started", "modify_ended", "desired_state", "operation", "final_state"}
+
+var statColumns = []string{"name", "value"}
+
+var moduleColumns = []string{"module", "depth", "resource_count", "cumulative_time_ms", "max_time_ms", "percent_of_total"}
+
+func (CSVRenderer) RenderTable(rows []ResourceRow) error {
+	w := csv.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	if err := w.Write(resourceColumns); err != nil {
+		return fmt.Errorf("failed to render csv output: %w", err)
+	}
+	for _, r := range rows {
+		record := []string{
+			r.Resource,
+			strconv.Itoa(r.NumCalls),
+			strconv.FormatInt(r.TotalTimeMillis, 10),
+			strconv.Itoa(r.ModificationStartedIndex),
+			strconv.Itoa(r.ModificationCompletedIndex),
+			r.DesiredStatus,
+			r.Operation,
+			r.AfterStatus,
+		}
+		if err := w.Write(record); err != nil {
+			return fmt.Errorf("failed to render csv output: %w", err)
+		}
+	}
+	return w.Error()
+}
+
+func (CSVRenderer) RenderStats(sections [][]StatRow) error {
+	w := csv.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	if err := w.Write(statColumns); err != nil {
+		return fmt.Errorf("failed to render csv output: %w", err)
+	}
+	for _, section := range sections {
+		for _, row := range section {
+			if err := w.Write([]string{row.Name, row.Value}); err != nil {
+				return fmt.Errorf("failed to render csv output: %w", err)
+			}
+		}
+	}
+	return w.Error()
+}
+
+// RenderModuleTree flattens the module tree depth-first, since CSV has no
+// way to express nesting; a "depth" column lets a consumer reconstruct it.
+func (CSVRenderer) RenderModuleTree(root ModuleNode) error {
+	w := csv.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	if err := w.Write(moduleColumns); err != nil {
+		return fmt.Errorf("failed to render csv output: %w", err)
+	}
+	var writeNode func(node ModuleNode, depth int) error
+	writeNode = func(node ModuleNode, depth int) error {
+		record := []string{
+			node.Module,
+			strconv.Itoa(depth),
+			strconv.Itoa(node.ResourceCount),
+			strconv.FormatInt(node.CumulativeTimeMillis, 10),
+			strconv.FormatInt(node.MaxTimeMillis, 10),
+			strconv.FormatFloat(node.PercentOfTotal, 'f', 2, 64),
+		}
+		if err := w.Write(record); err != nil {
+			return fmt.Errorf("failed to render csv output: %w", err)
+		}
+		for _, child := range node.Children {
+			if err := writeNode(child, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := writeNode(root, 0); err != nil {
+		return err
+	}
+	return w.Error()
+}
+
+// RenderDiff is unsupported: a diff report has two differently-shaped
+// tables (resource deltas and summary stats), and concatenating them into
+// one CSV stream produces a file whose column count changes partway
+// through, which chokes CSV readers like pandas.read_csv. Use json or
+// ndjson for `tf-profile diff` output instead.
+func (CSVRenderer) RenderDiff(report DiffReport) error {
+	return fmt.Errorf("csv output is not supported for diff (its resource deltas and summary stats don't share a schema); use --format json or --format ndjson instead")
+}
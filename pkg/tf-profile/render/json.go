@@ -0,0 +1,46 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// JSONRenderer emits a single JSON document so downstream tools (jq, pandas,
+// BI dashboards) can consume a tf-profile run without re-parsing the log.
+type JSONRenderer struct{}
+
+func (JSONRenderer) RenderTable(rows []ResourceRow) error {
+	return encodeJSON(struct {
+		Resources []ResourceRow `json:"resources"`
+	}{rows})
+}
+
+func (JSONRenderer) RenderStats(sections [][]StatRow) error {
+	stats := []StatRow{}
+	for _, section := range sections {
+		stats = append(stats, section...)
+	}
+	return encodeJSON(struct {
+		Stats []StatRow `json:"stats"`
+	}{stats})
+}
+
+func (JSONRenderer) RenderModuleTree(root ModuleNode) error {
+	return encodeJSON(struct {
+		Modules ModuleNode `json:"modules"`
+	}{root})
+}
+
+func (JSONRenderer) RenderDiff(report DiffReport) error {
+	return encodeJSON(report)
+}
+
+func encodeJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to render json output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// NDJSONRenderer emits one JSON object per line, for tools that consume
+// newline-delimited JSON streams rather than a single document.
+type NDJSONRenderer struct{}
+
+func (NDJSONRenderer) RenderTable(rows []ResourceRow) error {
+	enc := json.NewEncoder(os.Stdout)
+	for _, r := range rows {
+		if err := enc.Encode(r); err != nil {
+			return fmt.Errorf("failed to render ndjson output: %w", err)
+		}
+	}
+	return nil
+}
+
+func (NDJSONRenderer) RenderStats(sections [][]StatRow) error {
+	enc := json.NewEncoder(os.Stdout)
+	for _, section := range sections {
+		for _, row := range section {
+			if err := enc.Encode(row); err != nil {
+				return fmt.Errorf("failed to render ndjson output: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// RenderModuleTree emits one line per node, depth-first, each carrying its
+// own Children so a consumer can still reconstruct the tree if it wants to.
+func (NDJSONRenderer) RenderModuleTree(root ModuleNode) error {
+	enc := json.NewEncoder(os.Stdout)
+	var encodeNode func(node ModuleNode) error
+	encodeNode = func(node ModuleNode) error {
+		if err := enc.Encode(node); err != nil {
+			return fmt.Errorf("failed to render ndjson output: %w", err)
+		}
+		for _, child := range node.Children {
+			if err := encodeNode(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return encodeNode(root)
+}
+
+// RenderDiff emits one line per resource delta, followed by one line per
+// summary stat.
+func (NDJSONRenderer) RenderDiff(report DiffReport) error {
+	enc := json.NewEncoder(os.Stdout)
+	for _, delta := range report.Resources {
+		if err := enc.Encode(delta); err != nil {
+			return fmt.Errorf("failed to render ndjson output: %w", err)
+		}
+	}
+	for _, row := range report.Summary {
+		if err := enc.Encode(row); err != nil {
+			return fmt.Errorf("failed to render ndjson output: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package render
+
+import "fmt"
+
+// Format identifies a supported output format for Renderer implementations.
+type Format string
+
+const (
+	Text   Format = "text"
+	JSON   Format = "json"
+	CSV    Format = "csv"
+	NDJSON Format = "ndjson"
+)
+
+// StatRow is a single "key: value" line of `tf-profile stats` output. The
+// tfp tags make it sortable by pkg/tf-profile/sort for `stats --sort`.
+type StatRow struct {
+	Name  string `json:"name" tfp:"name"`
+	Value string `json:"value" tfp:"value"`
+}
+
+// ResourceRow is one row of `tf-profile table` output. It carries every
+// metric field (not just the ones the colored table prints) so downstream
+// tools can chart a Terraform run without re-parsing the log. The tfp tags
+// double as the table's column names, driving both header discovery and
+// `table --sort` via pkg/tf-profile/sort.
+type ResourceRow struct {
+	Resource                   string `json:"resource" csv:"resource" tfp:"resource"`
+	NumCalls                   int    `json:"num_calls" csv:"n" tfp:"n"`
+	TotalTimeMillis            int64  `json:"total_time_ms" csv:"tot_time_ms" tfp:"tot_time"`
+	ModificationStartedIndex   int    `json:"modification_started_index" csv:"modify_started" tfp:"modify_started"`
+	ModificationCompletedIndex int    `json:"modification_completed_index" csv:"modify_ended" tfp:"modify_ended"`
+	DesiredStatus              string `json:"desired_status" csv:"desired_state" tfp:"desired_state"`
+	Operation                  string `json:"operation" csv:"operation" tfp:"operation"`
+	AfterStatus                string `json:"final_state" csv:"final_state" tfp:"final_state"`
+}
+
+// ModuleNode is one node of the `tf-profile modules` tree: either the root
+// module (Module == "") or the dotted path of module names ("a.b" for
+// resources under "module.a.module.b"). Children are
+// sorted by CumulativeTimeMillis descending before rendering.
+type ModuleNode struct {
+	Module               string       `json:"module" csv:"module" tfp:"module"`
+	ResourceCount        int          `json:"resource_count" csv:"resource_count" tfp:"resource_count"`
+	CumulativeTimeMillis int64        `json:"cumulative_time_ms" csv:"cumulative_time_ms" tfp:"cumulative_time_ms"`
+	MaxTimeMillis        int64        `json:"max_time_ms" csv:"max_time_ms" tfp:"max_time_ms"`
+	PercentOfTotal       float64      `json:"percent_of_total" csv:"percent_of_total" tfp:"percent_of_total"`
+	Children             []ModuleNode `json:"children,omitempty" csv:"-" tfp:"-"`
+}
+
+// ResourceDelta is one row of `tf-profile diff` output: a resource's change
+// between two runs. Status is "new", "removed" or "changed" (a resource with
+// no observable change never reaches a renderer; see pkg/tf-profile/diff).
+type ResourceDelta struct {
+	Resource           string  `json:"resource" csv:"resource" tfp:"resource"`
+	Status             string  `json:"status" csv:"status" tfp:"status"`
+	OldTotalTimeMillis int64   `json:"old_total_time_ms" csv:"old_tot_time_ms" tfp:"old_tot_time_ms"`
+	NewTotalTimeMillis int64   `json:"new_total_time_ms" csv:"new_tot_time_ms" tfp:"new_tot_time_ms"`
+	DeltaTimeMillis    int64   `json:"delta_time_ms" csv:"delta_tot_time_ms" tfp:"delta_tot_time_ms"`
+	DeltaPercent       float64 `json:"delta_percent" csv:"delta_percent" tfp:"delta_percent"`
+	OldNumCalls        int     `json:"old_num_calls" csv:"old_n" tfp:"old_n"`
+	NewNumCalls        int     `json:"new_num_calls" csv:"new_n" tfp:"new_n"`
+	OldOperation       string  `json:"old_operation" csv:"old_operation" tfp:"old_operation"`
+	NewOperation       string  `json:"new_operation" csv:"new_operation" tfp:"new_operation"`
+	OldAfterStatus     string  `json:"old_final_state" csv:"old_final_state" tfp:"old_final_state"`
+	NewAfterStatus     string  `json:"new_final_state" csv:"new_final_state" tfp:"new_final_state"`
+}
+
+// DiffReport is the full result of `tf-profile diff`: per-resource deltas
+// (already filtered by --threshold) plus a summary section mirroring
+// stats' "Cumulative duration" style rows.
+type DiffReport struct {
+	Resources []ResourceDelta `json:"resources"`
+	Summary   []StatRow       `json:"summary"`
+}
+
+// Renderer turns tf-profile's resource rows, stat sections, module tree and
+// diff report into a specific output format. Stat sections are kept as a
+// slice of slices so renderers that care (e.g. the colored text table) can
+// reproduce the blank-row spacing between sections.
+type Renderer interface {
+	RenderTable(rows []ResourceRow) error
+	RenderStats(sections [][]StatRow) error
+	RenderModuleTree(root ModuleNode) error
+	RenderDiff(report DiffReport) error
+}
+
+// New returns the Renderer for the given format, defaulting to the colored
+// text table when format is empty.
+func New(format string) (Renderer, error) {
+	switch Format(format) {
+	case Text, "":
+		return TextRenderer{}, nil
+	case JSON:
+		return JSONRenderer{}, nil
+	case CSV:
+		return CSVRenderer{}, nil
+	case NDJSON:
+		return NDJSONRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q, expected one of text|json|csv|ndjson", format)
+	}
+}
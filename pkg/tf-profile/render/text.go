@@ -0,0 +1,150 @@
+package render
+
+import (
+	"fmt"
+
+	tfpsort "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/sort"
+	. "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/utils"
+	"github.com/fatih/color"
+	"github.com/rodaine/table"
+)
+
+// TextRenderer reproduces tf-profile's original colored terminal tables.
+type TextRenderer struct{}
+
+func (TextRenderer) RenderTable(rows []ResourceRow) error {
+	headerFmt := color.New(color.FgHiBlue, color.Underline).SprintfFunc()
+	columnFmt := color.New(color.FgBlue).SprintfFunc()
+
+	tbl := table.New(toInterfaceSlice(tfpsort.Headers(ResourceRow{}))...)
+	tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+
+	for _, r := range rows {
+		tbl.AddRow(
+			r.Resource,
+			r.NumCalls,
+			FormatDuration(int(r.TotalTimeMillis/1000)), // Display as "10s" or "1m30s"
+			removeMinusOne(r.ModificationStartedIndex),
+			removeMinusOne(r.ModificationCompletedIndex),
+			r.DesiredStatus,
+			r.Operation,
+			r.AfterStatus,
+		)
+	}
+
+	fmt.Println() // Create space above the table
+	tbl.Print()
+
+	return nil
+}
+
+func (TextRenderer) RenderStats(sections [][]StatRow) error {
+	headerFmt := color.New(color.FgHiBlue, color.Underline).SprintfFunc()
+	columnFmt := color.New(color.FgBlue).SprintfFunc()
+
+	tbl := table.New("Key", "Value")
+	tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+
+	for _, section := range sections {
+		for _, row := range section {
+			tbl.AddRow(row.Name, row.Value)
+		}
+		tbl.AddRow("", "") // Add some spacing between sections
+	}
+
+	fmt.Println() // Create space above the table
+	tbl.Print()
+
+	return nil
+}
+
+// RenderModuleTree prints an indented ASCII tree, one line per module, with
+// resource count, cumulative/max time and percent of total apply duration.
+func (TextRenderer) RenderModuleTree(root ModuleNode) error {
+	columnFmt := color.New(color.FgBlue).SprintfFunc()
+
+	fmt.Println() // Create space above the tree
+	var printNode func(node ModuleNode, prefix string)
+	printNode = func(node ModuleNode, prefix string) {
+		name := node.Module
+		if name == "" {
+			name = "(root)"
+		}
+		fmt.Printf("%v%v  %v\n", prefix, columnFmt(name),
+			fmt.Sprintf("n=%v tot=%v max=%v (%.1f%%)",
+				node.ResourceCount,
+				FormatDuration(int(node.CumulativeTimeMillis/1000)),
+				FormatDuration(int(node.MaxTimeMillis/1000)),
+				node.PercentOfTotal))
+		for _, child := range node.Children {
+			printNode(child, prefix+"  ")
+		}
+	}
+	printNode(root, "")
+
+	return nil
+}
+
+// RenderDiff prints the resource deltas as one colored table, followed by
+// the summary stats as a second "Key"/"Value" table.
+func (TextRenderer) RenderDiff(report DiffReport) error {
+	headerFmt := color.New(color.FgHiBlue, color.Underline).SprintfFunc()
+	columnFmt := color.New(color.FgBlue).SprintfFunc()
+
+	tbl := table.New("Resource", "Status", "Old Time", "New Time", "Delta", "Delta %", "Old Op", "New Op", "Old State", "New State")
+	tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+
+	for _, d := range report.Resources {
+		tbl.AddRow(
+			d.Resource,
+			d.Status,
+			FormatDuration(int(d.OldTotalTimeMillis/1000)),
+			FormatDuration(int(d.NewTotalTimeMillis/1000)),
+			signedDuration(d.DeltaTimeMillis),
+			fmt.Sprintf("%+.1f%%", d.DeltaPercent),
+			d.OldOperation,
+			d.NewOperation,
+			d.OldAfterStatus,
+			d.NewAfterStatus,
+		)
+	}
+
+	fmt.Println() // Create space above the table
+	tbl.Print()
+
+	if len(report.Summary) > 0 {
+		return TextRenderer{}.RenderStats([][]StatRow{report.Summary})
+	}
+	return nil
+}
+
+// signedDuration formats a (possibly negative) millisecond delta as e.g.
+// "+1m30s" or "-10s", since FormatDuration itself expects a non-negative value.
+func signedDuration(deltaMillis int64) string {
+	sign := "+"
+	if deltaMillis < 0 {
+		sign = "-"
+		deltaMillis = -deltaMillis
+	}
+	return sign + FormatDuration(int(deltaMillis/1000))
+}
+
+// Many metrics use -1 as value for "unknown at the time". When a resource change fails,
+// these initial values remain in the log. Before printing, we replace then with '/'
+func removeMinusOne(val int) string {
+	if val == -1 {
+		return "/"
+	} else {
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// toInterfaceSlice adapts a []string to the ...interface{} that rodaine/table
+// wants for a list of column headers.
+func toInterfaceSlice(headers []string) []interface{} {
+	result := make([]interface{}, len(headers))
+	for i, h := range headers {
+		result[i] = h
+	}
+	return result
+}
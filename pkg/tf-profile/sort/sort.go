@@ -0,0 +1,187 @@
+package tfprofile
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// FieldSpec is one key of a multi-key sort spec, e.g. "tot_time=desc".
+type FieldSpec struct {
+	Column string
+	Desc   bool
+}
+
+// ParseSpec parses a comma-separated sort spec such as
+// "operation=asc,tot_time=desc,resource=asc" into an ordered list of
+// FieldSpecs. A key without "=asc|desc" defaults to ascending.
+func ParseSpec(spec string) ([]FieldSpec, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+
+	fields := []FieldSpec{}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		column, dir, hasDir := strings.Cut(part, "=")
+		desc := false
+		if hasDir {
+			switch strings.ToLower(dir) {
+			case "asc":
+				desc = false
+			case "desc":
+				desc = true
+			default:
+				return nil, fmt.Errorf("invalid sort direction %q in %q, expected asc or desc", dir, part)
+			}
+		}
+		fields = append(fields, FieldSpec{Column: strings.TrimSpace(column), Desc: desc})
+	}
+	return fields, nil
+}
+
+// Headers returns the tfp tag of every field of sample, in
+// declaration order, falling back to the lowercased field name when a field
+// has no tfp tag. This lets table headers be generated from a struct
+// instead of hardcoded.
+func Headers(sample interface{}) []string {
+	t := reflect.TypeOf(sample)
+	headers := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		headers = append(headers, columnName(t.Field(i)))
+	}
+	return headers
+}
+
+// By sorts the slice rows in place according to spec, matching each key's
+// column against the tfp tag (or, failing that, the lowercased field name)
+// of rows' element type. Unknown columns are rejected before anything is
+// sorted. Ties between keys are broken in spec order; rows tied results are
+// rarely built in a stable order (they're typically assembled from a Go map,
+// whose iteration order is randomized per process), so any rows still tied
+// after the user's spec are deterministically broken on every remaining
+// field in declaration order, making output identical across runs of the
+// same input.
+func By(rows interface{}, spec string) error {
+	fields, err := ParseSpec(spec)
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	v := reflect.ValueOf(rows)
+	if v.Kind() != reflect.Slice {
+		return fmt.Errorf("sort: rows must be a slice, got %s", v.Kind())
+	}
+
+	elemType := v.Type().Elem()
+	lookup := columnIndex(elemType)
+	for _, f := range fields {
+		if _, ok := lookup[f.Column]; !ok {
+			return fmt.Errorf("unknown sort column %q, expected one of %v", f.Column, sortedKeys(lookup))
+		}
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		vi, vj := v.Index(i), v.Index(j)
+		for _, f := range fields {
+			idx := lookup[f.Column]
+			switch cmp := compare(vi.Field(idx), vj.Field(idx)); {
+			case cmp == 0:
+				continue
+			case f.Desc:
+				return cmp > 0
+			default:
+				return cmp < 0
+			}
+		}
+		return tiebreak(vi, vj, elemType.NumField())
+	})
+
+	return nil
+}
+
+// tiebreak deterministically orders two rows still tied after the user's
+// sort spec, comparing every field in declaration order ascending. This
+// replaces relying on sort.SliceStable's input order, which is meaningless
+// when rows were built by iterating a map.
+func tiebreak(vi reflect.Value, vj reflect.Value, numField int) bool {
+	for idx := 0; idx < numField; idx++ {
+		if cmp := compare(vi.Field(idx), vj.Field(idx)); cmp != 0 {
+			return cmp < 0
+		}
+	}
+	return false
+}
+
+// columnIndex maps each field's tfp column name to its field index.
+func columnIndex(t reflect.Type) map[string]int {
+	lookup := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		lookup[columnName(t.Field(i))] = i
+	}
+	return lookup
+}
+
+func columnName(field reflect.StructField) string {
+	if name := field.Tag.Get("tfp"); name != "" {
+		return name
+	}
+	return strings.ToLower(field.Name)
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// compare returns <0, 0 or >0 comparing two field values of the same kind.
+// It covers the kinds that appear in tf-profile's sortable structs: strings,
+// signed/unsigned integers and floats.
+func compare(a, b reflect.Value) int {
+	switch a.Kind() {
+	case reflect.String:
+		return strings.Compare(a.String(), b.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch {
+		case a.Int() < b.Int():
+			return -1
+		case a.Int() > b.Int():
+			return 1
+		default:
+			return 0
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch {
+		case a.Uint() < b.Uint():
+			return -1
+		case a.Uint() > b.Uint():
+			return 1
+		default:
+			return 0
+		}
+	case reflect.Float32, reflect.Float64:
+		switch {
+		case a.Float() < b.Float():
+			return -1
+		case a.Float() > b.Float():
+			return 1
+		default:
+			return 0
+		}
+	default:
+		return strings.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+	}
+}
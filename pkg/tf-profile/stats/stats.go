@@ -3,23 +3,27 @@ package tfprofile
 import (
 	"bufio"
 	"fmt"
+	"math"
 	"sort"
+	"strings"
 
 	. "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/aggregate"
 	. "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/core"
 	. "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/parser"
+	tfppercentile "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/percentile"
 	. "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/readers"
+	"github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/render"
+	tfpsort "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/sort"
 	. "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/utils"
-	"github.com/fatih/color"
-	"github.com/rodaine/table"
 )
 
-type Stat struct {
-	name  string
-	value string
-}
+// Stat is a single "key: value" line of `tf-profile stats` output.
+type Stat = render.StatRow
+
+// Default number of slowest resources to surface in the distribution section.
+const defaultTopN = 5
 
-func Stats(args []string, tee bool) error {
+func Stats(args []string, tee bool, format string, topN int, sortSpec string) error {
 	var file *bufio.Scanner
 	var err error
 
@@ -43,7 +47,7 @@ func Stats(args []string, tee bool) error {
 		return err
 	}
 
-	err = PrintStats(tflog)
+	err = PrintStats(tflog, format, topN, sortSpec)
 	if err != nil {
 		return err
 	}
@@ -51,33 +55,41 @@ func Stats(args []string, tee bool) error {
 	return nil
 }
 
-// Print various high-level stats about a ParsedLog
-func PrintStats(log ParsedLog) error {
-	headerFmt := color.New(color.FgHiBlue, color.Underline).SprintfFunc()
-	columnFmt := color.New(color.FgBlue).SprintfFunc()
-
-	tbl := table.New("Key", "Value")
-	tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
-
-	addRows(&tbl, getBasicStats(log))
-	addRows(&tbl, getTimeStats(log))
-	addRows(&tbl, getOperationStats(log))
-	addRows(&tbl, getAfterStatusStats(log))
-	addRows(&tbl, getDesiredStateStats(log))
-	addRows(&tbl, getModuleStats(log))
-
-	fmt.Println() // Create space above the table
-	tbl.Print()
+// Print various high-level stats about a ParsedLog.
+// format selects the Renderer used to print the result: text|json|csv|ndjson.
+// topN controls how many of the slowest resources are surfaced in the
+// distribution section; values <= 0 fall back to defaultTopN.
+// sortSpec, if non-empty, flattens every stats section into one and sorts
+// the rows using the same "column=asc|desc" syntax as `table --sort`,
+// matched against the tfp tags of render.StatRow (name, value).
+func PrintStats(log ParsedLog, format string, topN int, sortSpec string) error {
+	renderer, err := render.New(format)
+	if err != nil {
+		return err
+	}
 
-	return nil
-}
+	sections := [][]Stat{
+		getBasicStats(log),
+		getTimeStats(log),
+		getDistributionStats(log, topN),
+		getOperationStats(log),
+		getAfterStatusStats(log),
+		getDesiredStateStats(log),
+		getModuleStats(log),
+	}
 
-// Helper to add multiple rows at once
-func addRows(tbl *table.Table, rows []Stat) {
-	for _, stat := range rows {
-		(*tbl).AddRow(stat.name, stat.value)
+	if sortSpec != "" {
+		flat := []Stat{}
+		for _, section := range sections {
+			flat = append(flat, section...)
+		}
+		if err := tfpsort.By(flat, sortSpec); err != nil {
+			return err
+		}
+		sections = [][]Stat{flat}
 	}
-	(*tbl).AddRow("", "") // Add some spacing between sections
+
+	return renderer.RenderStats(sections)
 }
 
 func getBasicStats(log ParsedLog) []Stat {
@@ -86,7 +98,7 @@ func getBasicStats(log ParsedLog) []Stat {
 		NumCalls += resource.NumCalls
 	}
 	return []Stat{
-		{"Number of resources in configuration", fmt.Sprint(NumCalls)},
+		{Name: "Number of resources in configuration", Value: fmt.Sprint(NumCalls)},
 	}
 }
 
@@ -103,9 +115,9 @@ func getTimeStats(log ParsedLog) []Stat {
 		}
 	}
 	return []Stat{
-		{"Cumulative duration", FormatDuration(TotalTime)},
-		{"Longest apply time", FormatDuration(HighestTime / 1000)},
-		{"Longest apply resource", HighestResource},
+		{Name: "Cumulative duration", Value: FormatDuration(TotalTime)},
+		{Name: "Longest apply time", Value: FormatDuration(HighestTime / 1000)},
+		{Name: "Longest apply resource", Value: HighestResource},
 	}
 }
 
@@ -118,12 +130,12 @@ func getAfterStatusStats(log ParsedLog) []Stat {
 	result := []Stat{}
 	for status, count := range StatusCount {
 		StatName := fmt.Sprintf("Resources in state %v", status)
-		result = append(result, Stat{StatName, fmt.Sprint(count)})
+		result = append(result, Stat{Name: StatName, Value: fmt.Sprint(count)})
 	}
 
 	// Sort on name to make it consistent
 	sort.Slice(result, func(i int, j int) bool {
-		return result[i].name < result[j].name
+		return result[i].Name < result[j].Name
 	})
 	return result
 }
@@ -145,8 +157,8 @@ func getDesiredStateStats(log ParsedLog) []Stat {
 	percNotInDesired := 100 * float64(notInDesiredState) / float64(sum)
 
 	return []Stat{
-		{"Resources in desired state", fmt.Sprintf("%v out of %v (%.1f%%)", inDesiredState, sum, percInDesired)},
-		{"Resources not in desired state", fmt.Sprintf("%v out of %v (%.1f%%)", notInDesiredState, sum, percNotInDesired)},
+		{Name: "Resources in desired state", Value: fmt.Sprintf("%v out of %v (%.1f%%)", inDesiredState, sum, percInDesired)},
+		{Name: "Resources not in desired state", Value: fmt.Sprintf("%v out of %v (%.1f%%)", notInDesiredState, sum, percNotInDesired)},
 	}
 }
 
@@ -160,7 +172,7 @@ func getOperationStats(log ParsedLog) []Stat {
 	result := []Stat{}
 	for op, count := range Operations {
 		StatName := fmt.Sprintf("Resources marked for operation %v", op)
-		result = append(result, Stat{StatName, fmt.Sprint(count)})
+		result = append(result, Stat{Name: StatName, Value: fmt.Sprint(count)})
 	}
 	return result
 }
@@ -220,12 +232,148 @@ func getModuleStats(log ParsedLog) []Stat {
 	}
 
 	return []Stat{
-		{"Number of top-level modules", fmt.Sprint(len(toplevel))},
-		{"Largest top-level module", LargestTopLevelModule},
-		{"Size of largest top-level module", fmt.Sprint(LargestTopLevelModuleSize)},
-		{"Deepest module", DeepestModuleName},
-		{"Deepest module depth", fmt.Sprint(DeepestModuleDepth)},
-		{"Largest leaf module", LargestLeafModuleName},
-		{"Size of largest leaf module", fmt.Sprint(LargestLeafModuleSize)},
+		{Name: "Number of top-level modules", Value: fmt.Sprint(len(toplevel))},
+		{Name: "Largest top-level module", Value: LargestTopLevelModule},
+		{Name: "Size of largest top-level module", Value: fmt.Sprint(LargestTopLevelModuleSize)},
+		{Name: "Deepest module", Value: DeepestModuleName},
+		{Name: "Deepest module depth", Value: fmt.Sprint(DeepestModuleDepth)},
+		{Name: "Largest leaf module", Value: LargestLeafModuleName},
+		{Name: "Size of largest leaf module", Value: fmt.Sprint(LargestLeafModuleSize)},
+	}
+}
+
+// getDistributionStats turns the flat cumulative/max numbers from
+// getTimeStats into a real performance triage section: percentiles, mean,
+// stddev, a log-scale histogram and the topN slowest resources.
+func getDistributionStats(log ParsedLog, topN int) []Stat {
+	if topN <= 0 {
+		topN = defaultTopN
+	}
+
+	durations := make([]float64, 0, len(log.Resources))
+	for _, metric := range log.Resources {
+		durations = append(durations, float64(metric.TotalTime)/1000)
+	}
+	sort.Float64s(durations)
+
+	mean, stddev := meanAndStddev(durations)
+
+	result := []Stat{
+		{Name: "p50 apply time", Value: FormatDuration(int(tfppercentile.Of(durations, 0.50)))},
+		{Name: "p90 apply time", Value: FormatDuration(int(tfppercentile.Of(durations, 0.90)))},
+		{Name: "p95 apply time", Value: FormatDuration(int(tfppercentile.Of(durations, 0.95)))},
+		{Name: "p99 apply time", Value: FormatDuration(int(tfppercentile.Of(durations, 0.99)))},
+		{Name: "Mean apply time", Value: FormatDuration(int(mean))},
+		{Name: "Stddev of apply time", Value: fmt.Sprintf("%.1fs", stddev)},
+	}
+
+	result = append(result, histogramStats(durations)...)
+	result = append(result, topSlowestStats(log, topN)...)
+
+	return result
+}
+
+// durationBucket is one bar of the apply-time histogram; upper is the
+// exclusive upper bound of the bucket, in seconds.
+type durationBucket struct {
+	label string
+	upper float64
+}
+
+var histogramBuckets = []durationBucket{
+	{"<100ms", 0.1},
+	{"100ms-1s", 1},
+	{"1s-10s", 10},
+	{"10s-1m", 60},
+	{"1m-10m", 600},
+	{">10m", math.Inf(1)},
+}
+
+// histogramBarWidth is the width, in characters, of a full (max-count) bar.
+const histogramBarWidth = 40
+
+func histogramStats(durationsSeconds []float64) []Stat {
+	counts := make([]int, len(histogramBuckets))
+	for _, d := range durationsSeconds {
+		for i, bucket := range histogramBuckets {
+			if d < bucket.upper {
+				counts[i]++
+				break
+			}
+		}
+	}
+
+	maxCount := 0
+	for _, count := range counts {
+		if count > maxCount {
+			maxCount = count
+		}
+	}
+
+	result := make([]Stat, 0, len(histogramBuckets))
+	for i, bucket := range histogramBuckets {
+		bar := ""
+		if maxCount > 0 {
+			bar = strings.Repeat("#", counts[i]*histogramBarWidth/maxCount)
+		}
+		result = append(result, Stat{
+			Name:  fmt.Sprintf("Histogram %v", bucket.label),
+			Value: fmt.Sprintf("%v %v", counts[i], bar),
+		})
+	}
+	return result
+}
+
+func topSlowestStats(log ParsedLog, n int) []Stat {
+	type slowest struct {
+		name      string
+		totalTime int64
+	}
+
+	all := make([]slowest, 0, len(log.Resources))
+	for name, metric := range log.Resources {
+		all = append(all, slowest{name, int64(metric.TotalTime)})
 	}
+	// log.Resources is a map, so iteration order (and therefore the order of
+	// ties in all) is randomized per-process; break ties on name so the
+	// top-N list is deterministic across runs of the same log.
+	sort.Slice(all, func(i int, j int) bool {
+		if all[i].totalTime != all[j].totalTime {
+			return all[i].totalTime > all[j].totalTime
+		}
+		return all[i].name < all[j].name
+	})
+
+	if n > len(all) {
+		n = len(all)
+	}
+
+	result := make([]Stat, 0, n)
+	for i := 0; i < n; i++ {
+		result = append(result, Stat{
+			Name:  fmt.Sprintf("Top %v slowest resource", i+1),
+			Value: fmt.Sprintf("%v (%v)", all[i].name, FormatDuration(int(all[i].totalTime/1000))),
+		})
+	}
+	return result
+}
+
+func meanAndStddev(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	variance /= float64(len(values))
+
+	return mean, math.Sqrt(variance)
 }
@@ -2,21 +2,22 @@ package tfprofile
 
 import (
 	"bufio"
-	"fmt"
 
 	. "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/aggregate"
 	. "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/parser"
 	. "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/readers"
-	. "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/sort"
-	. "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/utils"
+	"github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/render"
+	tfpsort "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/sort"
 
 	. "github.com/QuintenBruynseraede/tf-profile/pkg/tf-profile/core"
-	"github.com/fatih/color"
-	"github.com/rodaine/table"
 )
 
+// defaultSortSpec keeps table output deterministic when the user doesn't
+// pass --sort.
+const defaultSortSpec = "resource=asc"
+
 // Execute the `tf-profile table` command
-func Table(args []string, max_depth int, tee bool, sort string) error {
+func Table(args []string, max_depth int, tee bool, sort string, format string) error {
 	var file *bufio.Scanner
 	var err error
 
@@ -40,7 +41,7 @@ func Table(args []string, max_depth int, tee bool, sort string) error {
 		return err
 	}
 
-	err = PrintTable(tflog, sort)
+	err = PrintTable(tflog, sort, format)
 	if err != nil {
 		return err
 	}
@@ -48,46 +49,38 @@ func Table(args []string, max_depth int, tee bool, sort string) error {
 	return nil
 }
 
-// Print a parsed log in tabular format, optionally sorting by certain columns
-// sort_spec is a comma-separated list of "column_name=(asc|desc)", e.g. "n=asc,tot_time=desc"
-func PrintTable(log ParsedLog, sort_spec string) error {
-	headerFmt := color.New(color.FgHiBlue, color.Underline).SprintfFunc()
-	columnFmt := color.New(color.FgBlue).SprintfFunc()
-
-	tbl := table.New("resource", "n", "tot_time", "modify_started", "modify_ended", "desired_state", "operation", "final_state")
-	tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
-
-	// Sort the resources according to the sort_spec and create rows
-	for _, r := range Sort(log, sort_spec) {
-		for resource, metric := range log.Resources {
-			if r == resource {
-				tbl.AddRow(
-					resource,
-					(metric.NumCalls),
-					FormatDuration(int(metric.TotalTime/1000)), // Display as "10s" or "1m30s"
-					removeMinusOne(metric.ModificationStartedIndex),
-					removeMinusOne(metric.ModificationCompletedIndex),
-					(metric.DesiredStatus),
-					(metric.Operation),
-					(metric.AfterStatus),
-				)
-				break
-			}
-		}
+// Print a parsed log in tabular format, optionally sorting by certain columns.
+// sort_spec is a comma-separated list of "column_name=(asc|desc)", e.g.
+// "n=asc,tot_time=desc"; columns are matched against the tfp tags of
+// render.ResourceRow, so any field of that struct is sortable. An empty
+// sort_spec falls back to defaultSortSpec.
+// format selects the Renderer used to print the result: text|json|csv|ndjson.
+func PrintTable(log ParsedLog, sort_spec string, format string) error {
+	renderer, err := render.New(format)
+	if err != nil {
+		return err
 	}
 
-	fmt.Println() // Create space above the table
-	tbl.Print()
-
-	return nil
-}
+	rows := make([]render.ResourceRow, 0, len(log.Resources))
+	for resource, metric := range log.Resources {
+		rows = append(rows, render.ResourceRow{
+			Resource:                   resource,
+			NumCalls:                   metric.NumCalls,
+			TotalTimeMillis:            int64(metric.TotalTime),
+			ModificationStartedIndex:   metric.ModificationStartedIndex,
+			ModificationCompletedIndex: metric.ModificationCompletedIndex,
+			DesiredStatus:              metric.DesiredStatus.String(),
+			Operation:                  metric.Operation.String(),
+			AfterStatus:                metric.AfterStatus.String(),
+		})
+	}
 
-// Many metrics use -1 as value for "unknown at the time". When a resource change fails,
-// these initial values remain in the log. Before printing, we replace then with '/'
-func removeMinusOne(val int) string {
-	if val == -1 {
-		return "/"
-	} else {
-		return fmt.Sprintf("%v", val)
+	if sort_spec == "" {
+		sort_spec = defaultSortSpec
 	}
+	if err := tfpsort.By(rows, sort_spec); err != nil {
+		return err
+	}
+
+	return renderer.RenderTable(rows)
 }